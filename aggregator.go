@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"devops-go-practice/internal/exporter"
+)
+
+// Aggregator owns one HostPoller per target host and fans their results
+// into a single channel, dispatching poll work across a bounded pool of
+// workers so polling a datacenter of hosts doesn't spawn one goroutine per
+// host.
+type Aggregator struct {
+	hosts        []string
+	workerCount  int
+	pollers      map[string]*HostPoller
+	window       *WindowEvaluator
+	monotonicity *MonotonicityGuard
+}
+
+// NewAggregator builds an Aggregator for hosts, polling through workerCount
+// concurrent workers. Every poll reports its outcome to registry; once a
+// host exhausts maxRetryCount, window and monotonicity are both told to
+// forget its samples so a later reconnect doesn't alert off, or get
+// discarded against, stale pre-outage data.
+func NewAggregator(hosts []string, workerCount int, registry *exporter.MetricsRegistry, window *WindowEvaluator, monotonicity *MonotonicityGuard) *Aggregator {
+	pollers := make(map[string]*HostPoller, len(hosts))
+	for _, host := range hosts {
+		pollers[host] = NewHostPoller(host, registry)
+	}
+	return &Aggregator{hosts: hosts, workerCount: workerCount, pollers: pollers, window: window, monotonicity: monotonicity}
+}
+
+// Run starts the worker pool and the polling ticker, returning the fanned-in
+// result channel. Each tick, every host still within maxRetryCount is handed
+// to a worker; workerCount bounds how many HTTP requests are in flight at
+// once regardless of how many hosts are configured.
+func (a *Aggregator) Run() <-chan HostResult {
+	jobs := make(chan *HostPoller)
+	results := make(chan HostResult)
+
+	for i := 0; i < a.workerCount; i++ {
+		go func() {
+			for poller := range jobs {
+				result := poller.poll()
+				poller.release()
+				results <- result
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+
+		for {
+			time.Sleep(requestInterval)
+
+			for _, host := range a.hosts {
+				poller := a.pollers[host]
+
+				if poller.exhausted() {
+					if poller.markReported() {
+						fmt.Printf("%s: не удалось получить статистику сервера после нескольких попыток.\n", host)
+						a.window.Reset(host)
+						a.monotonicity.Reset(host)
+					}
+					continue
+				}
+
+				if !poller.tryAcquire() {
+					// Previous poll for this host is still in flight (e.g.
+					// a slow response); skip this tick rather than running
+					// two polls against the same host concurrently.
+					continue
+				}
+
+				jobs <- poller
+			}
+		}
+	}()
+
+	return results
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Alert is a single threshold breach, carrying enough structure for any
+// AlertSink to render or forward it: which host and metric it's about, how
+// far out of bounds it is, and when it happened.
+type Alert struct {
+	Host       string
+	MetricName string
+	Severity   int
+	Value      int
+	Threshold  int
+	Timestamp  time.Time
+	Message    string
+}
+
+// alertQuotas caps how often each metric may alert per host; metrics not
+// listed fall back to AlertDispatcher's default quota.
+var alertQuotas = map[string]RateQuota{
+	"cpu_load":      PerMinute(6),
+	"memory_usage":  PerMinute(6),
+	"disk_usage":    PerMinute(3).WithBurst(2),
+	"network_usage": PerMinute(3).WithBurst(2),
+}
+
+// AlertDispatcher collects alerts from every host behind a priority queue
+// and flushes them worst-offender-first, so a wave of simultaneous alerts
+// doesn't scroll the most severe ones off screen behind minor ones. A
+// per-(host, metric) AlertLimiter keeps a sustained incident from flooding
+// the configured sinks with the same alert every requestInterval, and each
+// sink gets its own buffered, drop-oldest queue so a slow or failing sink
+// never blocks the polling goroutine.
+type AlertDispatcher struct {
+	mu      sync.Mutex
+	queue   *PriorityQueue[Alert]
+	limiter *AlertLimiter
+	sinks   []*sinkQueue
+}
+
+// NewAlertDispatcher builds an AlertDispatcher fanning admitted alerts out
+// to sinks. With no sinks given, it falls back to printing to stdout.
+func NewAlertDispatcher(sinks ...AlertSink) *AlertDispatcher {
+	if len(sinks) == 0 {
+		sinks = []AlertSink{StdoutSink{}}
+	}
+
+	queues := make([]*sinkQueue, len(sinks))
+	for i, sink := range sinks {
+		queues[i] = newSinkQueue(sink)
+	}
+
+	return &AlertDispatcher{
+		queue:   NewPriorityQueue[Alert](),
+		limiter: NewAlertLimiter(NewMemoryStore(), PerMinute(4), alertQuotas),
+		sinks:   queues,
+	}
+}
+
+// Submit queues alert for the next Flush, ordered by its Severity.
+func (d *AlertDispatcher) Submit(alert Alert) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.queue.Push(alert, alert.Severity)
+}
+
+// Flush forwards every pending alert to the configured sinks, highest
+// severity first, then drains the queue.
+func (d *AlertDispatcher) Flush() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for {
+		alert, ok := d.queue.Pop()
+		if !ok {
+			return
+		}
+
+		admit, suppressed := d.limiter.Allow(alert.Host, alert.MetricName)
+		if !admit {
+			continue
+		}
+
+		if suppressed > 0 {
+			d.fanOut(Alert{
+				Host:       alert.Host,
+				MetricName: alert.MetricName,
+				Timestamp:  time.Now(),
+				Message:    fmt.Sprintf("%s alert suppressed %d× in last minute\n", alert.MetricName, suppressed),
+			})
+		}
+		d.fanOut(alert)
+	}
+}
+
+func (d *AlertDispatcher) fanOut(alert Alert) {
+	for _, sink := range d.sinks {
+		sink.enqueue(alert)
+	}
+}
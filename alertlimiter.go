@@ -0,0 +1,124 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// RateQuota configures how often a (host, metric) pair is allowed to emit
+// an alert: EmissionInterval is the steady-state spacing between admitted
+// events, Burst is how many extra events beyond that steady rate may be
+// admitted back-to-back before the limiter starts suppressing.
+type RateQuota struct {
+	EmissionInterval time.Duration
+	Burst            int
+}
+
+// PerMinute builds a RateQuota admitting n events per minute at the steady
+// state, with no burst allowance.
+func PerMinute(n int) RateQuota {
+	return RateQuota{EmissionInterval: time.Minute / time.Duration(n), Burst: 0}
+}
+
+// WithBurst returns a copy of q allowing burst extra events on top of its
+// steady rate.
+func (q RateQuota) WithBurst(burst int) RateQuota {
+	q.Burst = burst
+	return q
+}
+
+// gcraState is the per-key GCRA bookkeeping: the theoretical arrival time
+// (TAT) of the next conforming event, plus how many events have been
+// suppressed since one was last admitted.
+type gcraState struct {
+	tat        time.Time
+	suppressed int
+}
+
+// LimiterStore abstracts where GCRA state lives, so AlertLimiter can later
+// be pointed at Redis instead of this in-memory map without changing its
+// own logic.
+type LimiterStore interface {
+	Load(key string) (gcraState, bool)
+	Store(key string, state gcraState)
+}
+
+type memoryStore struct {
+	mu     sync.Mutex
+	states map[string]gcraState
+}
+
+// NewMemoryStore builds an in-memory LimiterStore.
+func NewMemoryStore() LimiterStore {
+	return &memoryStore{states: make(map[string]gcraState)}
+}
+
+func (s *memoryStore) Load(key string) (gcraState, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[key]
+	return state, ok
+}
+
+func (s *memoryStore) Store(key string, state gcraState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[key] = state
+}
+
+// AlertLimiter rate-limits alerts per (host, metric) using the Generic Cell
+// Rate Algorithm, so a sustained incident can't flood stdout with the same
+// alert every requestInterval.
+type AlertLimiter struct {
+	mu           sync.Mutex
+	store        LimiterStore
+	defaultQuota RateQuota
+	quotas       map[string]RateQuota
+}
+
+// NewAlertLimiter builds an AlertLimiter backed by store. quotas maps metric
+// name to its RateQuota; metrics absent from quotas fall back to
+// defaultQuota.
+func NewAlertLimiter(store LimiterStore, defaultQuota RateQuota, quotas map[string]RateQuota) *AlertLimiter {
+	return &AlertLimiter{store: store, defaultQuota: defaultQuota, quotas: quotas}
+}
+
+func (l *AlertLimiter) quotaFor(metricName string) RateQuota {
+	if quota, ok := l.quotas[metricName]; ok {
+		return quota
+	}
+	return l.defaultQuota
+}
+
+// Allow applies the GCRA test for (host, metricName) and reports whether
+// the event should be admitted now. When admit is true, suppressed is how
+// many prior events were dropped since the bucket last admitted one, so the
+// caller can fold that count into a summary alert.
+func (l *AlertLimiter) Allow(host, metricName string) (admit bool, suppressed int) {
+	quota := l.quotaFor(metricName)
+	key := host + "|" + metricName
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	state, _ := l.store.Load(key)
+
+	tat := state.tat
+	if tat.Before(now) {
+		tat = now
+	}
+
+	burstOffset := quota.EmissionInterval * time.Duration(quota.Burst)
+	newTAT := tat.Add(quota.EmissionInterval)
+
+	if now.Before(tat.Add(-burstOffset)) {
+		state.suppressed++
+		l.store.Store(key, state)
+		return false, 0
+	}
+
+	suppressed = state.suppressed
+	l.store.Store(key, gcraState{tat: newTAT})
+	return true, suppressed
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAlertLimiterAllow_BurstAdmitsConfiguredCount(t *testing.T) {
+	quota := RateQuota{EmissionInterval: time.Hour, Burst: 2}
+	limiter := NewAlertLimiter(NewMemoryStore(), quota, nil)
+
+	for i := 0; i < 3; i++ {
+		admit, _ := limiter.Allow("host-a", "disk_usage")
+		if !admit {
+			t.Fatalf("event %d: expected admit (1 steady + Burst=2), got suppressed", i+1)
+		}
+	}
+
+	if admit, _ := limiter.Allow("host-a", "disk_usage"); admit {
+		t.Fatal("4th back-to-back event should be suppressed once the burst is exhausted")
+	}
+}
+
+func TestAlertLimiterAllow_ReportsSuppressedCountOnNextAdmission(t *testing.T) {
+	quota := RateQuota{EmissionInterval: 20 * time.Millisecond, Burst: 0}
+	limiter := NewAlertLimiter(NewMemoryStore(), quota, nil)
+
+	if admit, _ := limiter.Allow("host-a", "cpu_load"); !admit {
+		t.Fatal("first event should be admitted")
+	}
+
+	wantSuppressed := 3
+	for i := 0; i < wantSuppressed; i++ {
+		if admit, _ := limiter.Allow("host-a", "cpu_load"); admit {
+			t.Fatalf("event %d: expected suppression before EmissionInterval elapses", i+1)
+		}
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	admit, suppressed := limiter.Allow("host-a", "cpu_load")
+	if !admit {
+		t.Fatal("expected admission once EmissionInterval has elapsed")
+	}
+	if suppressed != wantSuppressed {
+		t.Fatalf("suppressed = %d, want %d", suppressed, wantSuppressed)
+	}
+}
+
+func TestAlertLimiterAllow_KeyedPerHostAndMetric(t *testing.T) {
+	quota := RateQuota{EmissionInterval: time.Hour, Burst: 0}
+	limiter := NewAlertLimiter(NewMemoryStore(), quota, nil)
+
+	if admit, _ := limiter.Allow("host-a", "cpu_load"); !admit {
+		t.Fatal("first event for (host-a, cpu_load) should be admitted")
+	}
+	if admit, _ := limiter.Allow("host-b", "cpu_load"); !admit {
+		t.Fatal("a different host should have its own independent bucket")
+	}
+	if admit, _ := limiter.Allow("host-a", "memory_usage"); !admit {
+		t.Fatal("a different metric on the same host should have its own independent bucket")
+	}
+}
+
+func TestAlertLimiterQuotaFor_FallsBackToDefault(t *testing.T) {
+	defaultQuota := RateQuota{EmissionInterval: time.Minute, Burst: 0}
+	specific := RateQuota{EmissionInterval: time.Second, Burst: 5}
+	limiter := NewAlertLimiter(NewMemoryStore(), defaultQuota, map[string]RateQuota{"disk_usage": specific})
+
+	if got := limiter.quotaFor("disk_usage"); got != specific {
+		t.Fatalf("quotaFor(disk_usage) = %+v, want %+v", got, specific)
+	}
+	if got := limiter.quotaFor("cpu_load"); got != defaultQuota {
+		t.Fatalf("quotaFor(cpu_load) = %+v, want default %+v", got, defaultQuota)
+	}
+}
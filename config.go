@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// defaultThresholdsConfigPath is where this tool looks for its threshold
+// config by default; pass -thresholds to override.
+const defaultThresholdsConfigPath = "thresholds.json"
+
+// rawThresholdsConfig is the on-disk shape of the threshold config file:
+// one human-readable quantity per metric name, e.g. {"disk_usage": "2Gi"}.
+type rawThresholdsConfig map[string]string
+
+// defaultThresholds preserves this tool's original fixed-percentage rules
+// for any metric missing from the config file.
+var defaultThresholds = rawThresholdsConfig{
+	"cpu_load":      "30%",
+	"memory_usage":  "80%",
+	"disk_usage":    "90%",
+	"network_usage": "90%",
+}
+
+// loadThresholds reads a JSON threshold config from path and parses each
+// entry with ParseQuantity. A missing file is not an error: the tool falls
+// back to defaultThresholds so it still runs out of the box.
+func loadThresholds(path string) (map[string]Threshold, error) {
+	raw := make(rawThresholdsConfig, len(defaultThresholds))
+	for name, quantity := range defaultThresholds {
+		raw[name] = quantity
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("parse thresholds config %s: %w", path, err)
+		}
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("read thresholds config %s: %w", path, err)
+	}
+
+	thresholds := make(map[string]Threshold, len(raw))
+	for name, quantity := range raw {
+		threshold, err := ParseQuantity(quantity)
+		if err != nil {
+			return nil, fmt.Errorf("threshold %q: %w", name, err)
+		}
+		thresholds[name] = threshold
+	}
+
+	return thresholds, nil
+}
@@ -0,0 +1,168 @@
+// Package exporter serves the tool's own collected stats as Prometheus text
+// exposition format so an existing Prometheus/Grafana stack can scrape it
+// instead of only reading stdout.
+package exporter
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the poll_duration_seconds histogram bucket boundaries,
+// in seconds.
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// latencyHistogram holds the running Prometheus-style bucket counts for one
+// host's poll_duration_seconds series: cumulative counts keyed the same as
+// latencyBuckets, plus the running sum and count needed for the _sum/_count
+// series. Keeping only these running totals (rather than every raw sample)
+// is what keeps ObservePollLatency's memory bounded across a long-running
+// process.
+type latencyHistogram struct {
+	cumulative []int64
+	sum        float64
+	count      int64
+}
+
+// observe folds one sample into the running bucket counts.
+func (h *latencyHistogram) observe(seconds float64) {
+	if h.cumulative == nil {
+		h.cumulative = make([]int64, len(latencyBuckets))
+	}
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.cumulative[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// MetricsRegistry holds every series this tool exposes: per-host gauges for
+// the latest sample of each resource, counters for poll outcomes, and a
+// histogram of poll latency. All series are labeled by host.
+type MetricsRegistry struct {
+	mu sync.Mutex
+
+	gauges    map[string]map[string]float64
+	counters  map[string]map[string]int64
+	latencies map[string]*latencyHistogram
+}
+
+// NewRegistry builds an empty MetricsRegistry.
+func NewRegistry() *MetricsRegistry {
+	return &MetricsRegistry{
+		gauges:    make(map[string]map[string]float64),
+		counters:  make(map[string]map[string]int64),
+		latencies: make(map[string]*latencyHistogram),
+	}
+}
+
+// SetGauge sets the current value of gauge name for host, e.g. "cpu_load".
+func (r *MetricsRegistry) SetGauge(name, host string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.gauges[name] == nil {
+		r.gauges[name] = make(map[string]float64)
+	}
+	r.gauges[name][host] = value
+}
+
+// IncCounter bumps counter name for host by one, e.g. "poll_errors_total".
+func (r *MetricsRegistry) IncCounter(name, host string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.counters[name] == nil {
+		r.counters[name] = make(map[string]int64)
+	}
+	r.counters[name][host]++
+}
+
+// ObservePollLatency records one poll_duration_seconds sample for host,
+// folding it into host's running bucket counts rather than retaining the
+// raw sample.
+func (r *MetricsRegistry) ObservePollLatency(host string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.latencies[host] == nil {
+		r.latencies[host] = &latencyHistogram{}
+	}
+	r.latencies[host].observe(d.Seconds())
+}
+
+// ServeHTTP renders every series in Prometheus text exposition format.
+func (r *MetricsRegistry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	r.writeGauges(w)
+	r.writeCounters(w)
+	r.writeLatencyHistogram(w)
+}
+
+func (r *MetricsRegistry) writeGauges(w io.Writer) {
+	for _, name := range sortedKeys(r.gauges) {
+		fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+		for _, host := range sortedKeys(r.gauges[name]) {
+			fmt.Fprintf(w, "%s{host=%q} %v\n", name, host, r.gauges[name][host])
+		}
+	}
+}
+
+func (r *MetricsRegistry) writeCounters(w io.Writer) {
+	for _, name := range sortedKeys(r.counters) {
+		fmt.Fprintf(w, "# TYPE %s counter\n", name)
+		for _, host := range sortedKeys(r.counters[name]) {
+			fmt.Fprintf(w, "%s{host=%q} %d\n", name, host, r.counters[name][host])
+		}
+	}
+}
+
+func (r *MetricsRegistry) writeLatencyHistogram(w io.Writer) {
+	const name = "poll_duration_seconds"
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+
+	for _, host := range sortedKeys(r.latencies) {
+		hist := r.latencies[host]
+
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(w, "%s_bucket{host=%q,le=%q} %d\n", name, host, fmt.Sprint(bound), hist.cumulative[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{host=%q,le=\"+Inf\"} %d\n", name, host, hist.count)
+		fmt.Fprintf(w, "%s_sum{host=%q} %v\n", name, host, hist.sum)
+		fmt.Fprintf(w, "%s_count{host=%q} %d\n", name, host, hist.count)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// StartServer starts an HTTP server exposing registry on addr's "/metrics"
+// path and returns it so the caller can Shutdown it on exit.
+func StartServer(addr string, registry *MetricsRegistry) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", registry)
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Printf("exporter: не удалось запустить HTTP-сервер: %s\n", err)
+		}
+	}()
+
+	return server
+}
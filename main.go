@@ -1,98 +1,194 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"io"
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
+
+	"devops-go-practice/internal/exporter"
 )
 
 const (
-	serverURL       = "http://srv.msk01.gigacorp.local/_stats"
 	maxRetryCount   = 3
 	httpTimeout     = 5 * time.Second
 	requestInterval = 500 * time.Millisecond
+	maxPollWorkers  = 10
+	exporterAddr    = ":9100"
 
 	expectedMetricsLength = 7
-
-	cpuLoadThreshold      = 30
-	memoryUsageThreshold  = 80
-	diskUsageThreshold    = 90
-	networkUsageThreshold = 90
 )
 
+// defaultTargetHosts is the fallback "sysstats" endpoint to poll when
+// -hosts isn't given. In production the host list would come from service
+// discovery; a hundred machines is the use case the worker pool in
+// Aggregator.Run is sized for.
+var defaultTargetHosts = []string{
+	"http://srv.msk01.gigacorp.local/_stats",
+}
+
+// parseTargetHosts splits a comma-separated -hosts flag value into a host
+// list, falling back to defaultTargetHosts when raw is empty.
+func parseTargetHosts(raw string) []string {
+	if raw == "" {
+		return defaultTargetHosts
+	}
+
+	hosts := strings.Split(raw, ",")
+	for i, host := range hosts {
+		hosts[i] = strings.TrimSpace(host)
+	}
+	return hosts
+}
+
 type Metric struct {
+	name       string
 	capacity   int
 	usage      int
-	threshold  int
+	threshold  Threshold
 	message    string
 	unit       string
 	checkUsage func(capacity, usage int) (int, int)
 }
 
+// severity scores how badly a metric is out of bounds so the alert
+// dispatcher can rank simultaneous alerts across many hosts, normalized
+// against the threshold's own headroom so a near-total exhaustion ranks
+// above a narrow miss regardless of threshold kind or unit.
+func (m Metric) severity(usagePercent, freeResource int) int {
+	if m.threshold.Kind == ThresholdFreeResource {
+		if m.threshold.Value <= 0 {
+			return 0
+		}
+		return (m.threshold.Value - freeResource) * 100 / m.threshold.Value
+	}
+
+	if m.unit == "%" || m.unit == "" {
+		return usagePercent - m.threshold.Value
+	}
+
+	headroom := 100 - m.threshold.Value
+	if headroom <= 0 {
+		headroom = 1
+	}
+	return (usagePercent - m.threshold.Value) * 100 / headroom
+}
+
 func main() {
-	resultStream := startPolling(serverURL, maxRetryCount)
+	var sinks sinkFlags
+	flag.Var(&sinks, "sink", "alert sink to fan alerts out to (stdout, file:<path>, webhook:<url>, amqp:<addr>); may be repeated")
+	thresholdsPath := flag.String("thresholds", defaultThresholdsConfigPath, "path to a JSON file of per-metric alert thresholds")
+	hostsFlag := flag.String("hosts", "", "comma-separated list of target host URLs to poll (defaults to a single built-in host)")
+	flag.Parse()
 
-	for response := range resultStream() {
-		metrics, err := parseMetrics(response)
-		if err != nil {
-			fmt.Println("Error parsing metrics:", err)
+	thresholds, err := loadThresholds(*thresholdsPath)
+	if err != nil {
+		fmt.Println("Error loading thresholds:", err)
+		return
+	}
+
+	registry := exporter.NewRegistry()
+	exporter.StartServer(exporterAddr, registry)
+
+	window := NewWindowEvaluator(defaultWindowConfig)
+	monotonicity := NewMonotonicityGuard()
+
+	aggregator := NewAggregator(parseTargetHosts(*hostsFlag), maxPollWorkers, registry, window, monotonicity)
+	dispatcher := NewAlertDispatcher(buildSinks(sinks)...)
+
+	flushTicker := time.NewTicker(requestInterval)
+	defer flushTicker.Stop()
+
+	go func() {
+		for range flushTicker.C {
+			dispatcher.Flush()
+		}
+	}()
+
+	for result := range aggregator.Run() {
+		if result.Err != nil {
 			continue
 		}
 
-		metricList := []Metric{
-			{
-				capacity:   metrics.CPULoad,
-				usage:      metrics.CPULoad,
-				threshold:  cpuLoadThreshold,
-				message:    "Load Average is too high: %d\n",
-				unit:       "",
-				checkUsage: getDirectUsage,
-			},
-			{
-				capacity:   metrics.MemoryCapacity,
-				usage:      metrics.MemoryUsage,
-				threshold:  memoryUsageThreshold,
-				message:    "Memory usage too high: %d%%\n",
-				unit:       "%",
-				checkUsage: getPercentageUsage,
-			},
-			{
-				capacity:   metrics.DiskCapacity,
-				usage:      metrics.DiskUsage,
-				threshold:  diskUsageThreshold,
-				message:    "Free disk space is too low: %d Mb left\n",
-				unit:       "Mb",
-				checkUsage: getFreeDiskSpace,
-			},
-			{
-				capacity:   metrics.NetworkCapacity,
-				usage:      metrics.NetworkActivity,
-				threshold:  networkUsageThreshold,
-				message:    "Network bandwidth usage high: %d Mbit/s available\n",
-				unit:       "Mbit/s",
-				checkUsage: getFreeNetworkBandwidth,
-			},
+		if !monotonicity.Check(result.Host, result.Metrics) {
+			continue
 		}
 
-		for _, metric := range metricList {
-			validateResourceUsage(metric)
+		for _, metric := range buildMetricList(result.Metrics, thresholds) {
+			if alert, ok := evaluateMetric(result.Host, metric, window); ok {
+				dispatcher.Submit(alert)
+			}
 		}
 	}
 }
 
-func validateResourceUsage(m Metric) {
+func buildMetricList(metrics ServerMetrics, thresholds map[string]Threshold) []Metric {
+	return []Metric{
+		{
+			name:       "cpu_load",
+			capacity:   metrics.CPULoad,
+			usage:      metrics.CPULoad,
+			threshold:  thresholds["cpu_load"],
+			message:    "Load Average is too high: %d\n",
+			unit:       "",
+			checkUsage: getDirectUsage,
+		},
+		{
+			name:       "memory_usage",
+			capacity:   metrics.MemoryCapacity,
+			usage:      metrics.MemoryUsage,
+			threshold:  thresholds["memory_usage"],
+			message:    "Memory usage too high: %d%%\n",
+			unit:       "%",
+			checkUsage: getPercentageUsage,
+		},
+		{
+			name:       "disk_usage",
+			capacity:   metrics.DiskCapacity,
+			usage:      metrics.DiskUsage,
+			threshold:  thresholds["disk_usage"],
+			message:    "Free disk space is too low: %d Mb left\n",
+			unit:       "Mb",
+			checkUsage: getFreeDiskSpace,
+		},
+		{
+			name:       "network_usage",
+			capacity:   metrics.NetworkCapacity,
+			usage:      metrics.NetworkActivity,
+			threshold:  thresholds["network_usage"],
+			message:    "Network bandwidth usage high: %d Mbit/s available\n",
+			unit:       "Mbit/s",
+			checkUsage: getFreeNetworkBandwidth,
+		},
+	}
+}
+
+// evaluateMetric checks a single metric against its threshold - dispatching
+// the comparison to usagePercent or freeResource based on the threshold's
+// Kind - and, once window reports the breach as sustained rather than a
+// single spike, builds the Alert an AlertDispatcher can rank and print.
+func evaluateMetric(host string, m Metric, window *WindowEvaluator) (Alert, bool) {
 	usagePercent, freeResource := m.checkUsage(m.capacity, m.usage)
+	sustained := window.Observe(host, m.name, m.threshold.Breached(usagePercent, freeResource))
+	if !sustained {
+		return Alert{}, false
+	}
 
-	if usagePercent > m.threshold {
-		if m.unit == "%" || m.unit == "" {
-			fmt.Printf(m.message, usagePercent)
-		} else {
-			fmt.Printf(m.message, freeResource)
-		}
+	value := usagePercent
+	if m.unit != "%" && m.unit != "" {
+		value = freeResource
 	}
+
+	return Alert{
+		Host:       host,
+		MetricName: m.name,
+		Severity:   m.severity(usagePercent, freeResource),
+		Value:      value,
+		Threshold:  m.threshold.Value,
+		Timestamp:  time.Now(),
+		Message:    fmt.Sprintf(m.message, value),
+	}, true
 }
 
 func getDirectUsage(capacity, _ int) (int, int) {
@@ -122,65 +218,6 @@ func getFreeNetworkBandwidth(capacity, usage int) (int, int) {
 	return usage * 100 / capacity, freeResource
 }
 
-func startPolling(url string, retries int) func() chan string {
-	return func() chan string {
-		dataChannel := make(chan string)
-		client := http.Client{Timeout: httpTimeout}
-		errorCounter := 0
-
-		go func() {
-			defer close(dataChannel)
-
-			for {
-				time.Sleep(requestInterval)
-
-				if errorCounter >= retries {
-					fmt.Println("Не удалось получить статистику сервера после нескольких попыток.")
-					break
-				}
-
-				response, err := client.Get(url)
-				errorCounter = processResponseError(response, err, errorCounter)
-				if errorCounter > 0 {
-					continue
-				}
-
-				body, err := io.ReadAll(response.Body)
-				if err != nil {
-					errorCounter = incrementErrorCount(err, errorCounter, "не удалось прочитать тело ответа")
-					continue
-				}
-
-				response.Body.Close()
-				dataChannel <- string(body)
-
-				errorCounter = 0
-			}
-		}()
-
-		return dataChannel
-	}
-}
-
-func processResponseError(response *http.Response, err error, errorCounter int) int {
-	if err != nil {
-		fmt.Printf("Ошибка при отправке запроса: %s\n", err)
-		return incrementErrorCount(err, errorCounter, "")
-	}
-	if response.StatusCode != http.StatusOK {
-		fmt.Printf("Неверный код статуса: %d\n", response.StatusCode)
-		return incrementErrorCount(fmt.Errorf("invalid status code"), errorCounter, "")
-	}
-	return errorCounter
-}
-
-func incrementErrorCount(err error, errorCounter int, message string) int {
-	if message != "" {
-		fmt.Printf("%s: %s\n", message, err)
-	}
-	return errorCounter + 1
-}
-
 type ServerMetrics struct {
 	CPULoad         int
 	MemoryCapacity  int
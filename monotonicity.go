@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+)
+
+// monotonicityShrinkTolerance is how far a capacity reading may drop
+// between consecutive samples before it's treated as an impossible
+// regression rather than a real resize.
+const monotonicityShrinkTolerance = 0.5
+
+// MonotonicityGuard rejects ServerMetrics samples that regress in ways a
+// real host shouldn't, adopting the monotonicity-guard idea from
+// Kubernetes metrics-server: capacities should only grow or stay put, so a
+// drastic shrink almost always means a corrupt or stale reading.
+type MonotonicityGuard struct {
+	mu       sync.Mutex
+	lastSeen map[string]ServerMetrics
+}
+
+// NewMonotonicityGuard builds an empty MonotonicityGuard.
+func NewMonotonicityGuard() *MonotonicityGuard {
+	return &MonotonicityGuard{lastSeen: make(map[string]ServerMetrics)}
+}
+
+// Check compares metrics against the last accepted sample for host. It
+// returns false if the sample should be discarded, having already logged
+// why.
+func (g *MonotonicityGuard) Check(host string, metrics ServerMetrics) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	previous, seen := g.lastSeen[host]
+	if seen && capacityShrankDrastically(previous, metrics) {
+		fmt.Printf("%s: unexpected decrease in reported capacity, discarding sample\n", host)
+		return false
+	}
+
+	g.lastSeen[host] = metrics
+	return true
+}
+
+// Reset forgets host's last accepted sample, e.g. after a reconnect, so the
+// next sample isn't compared against data from before the outage.
+func (g *MonotonicityGuard) Reset(host string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.lastSeen, host)
+}
+
+func capacityShrankDrastically(previous, current ServerMetrics) bool {
+	return shrankDrastically(previous.DiskCapacity, current.DiskCapacity) ||
+		shrankDrastically(previous.MemoryCapacity, current.MemoryCapacity) ||
+		shrankDrastically(previous.NetworkCapacity, current.NetworkCapacity)
+}
+
+func shrankDrastically(previous, current int) bool {
+	if previous <= 0 {
+		return false
+	}
+	return float64(current) < float64(previous)*monotonicityShrinkTolerance
+}
@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+func TestMonotonicityGuard_AcceptsFirstSampleForHost(t *testing.T) {
+	g := NewMonotonicityGuard()
+
+	if !g.Check("host-a", ServerMetrics{DiskCapacity: 1000}) {
+		t.Fatal("the first sample seen for a host should always be accepted")
+	}
+}
+
+func TestMonotonicityGuard_AcceptsGrowthAndSteadyState(t *testing.T) {
+	g := NewMonotonicityGuard()
+
+	g.Check("host-a", ServerMetrics{DiskCapacity: 1000, MemoryCapacity: 500, NetworkCapacity: 100})
+
+	if !g.Check("host-a", ServerMetrics{DiskCapacity: 1000, MemoryCapacity: 500, NetworkCapacity: 100}) {
+		t.Fatal("an identical follow-up sample should be accepted")
+	}
+	if !g.Check("host-a", ServerMetrics{DiskCapacity: 2000, MemoryCapacity: 500, NetworkCapacity: 100}) {
+		t.Fatal("growth in capacity should be accepted")
+	}
+}
+
+func TestMonotonicityGuard_RejectsDrasticShrink(t *testing.T) {
+	g := NewMonotonicityGuard()
+
+	g.Check("host-a", ServerMetrics{DiskCapacity: 1000})
+
+	if g.Check("host-a", ServerMetrics{DiskCapacity: 400}) {
+		t.Fatal("a capacity dropping below the shrink tolerance should be rejected")
+	}
+}
+
+func TestMonotonicityGuard_AcceptsShrinkWithinTolerance(t *testing.T) {
+	g := NewMonotonicityGuard()
+
+	g.Check("host-a", ServerMetrics{DiskCapacity: 1000})
+
+	if !g.Check("host-a", ServerMetrics{DiskCapacity: 600}) {
+		t.Fatal("a capacity shrinking to exactly the tolerance boundary should still be accepted")
+	}
+}
+
+func TestMonotonicityGuard_RejectedSampleDoesNotBecomeTheNewBaseline(t *testing.T) {
+	g := NewMonotonicityGuard()
+
+	g.Check("host-a", ServerMetrics{DiskCapacity: 1000})
+	g.Check("host-a", ServerMetrics{DiskCapacity: 400})
+
+	if !g.Check("host-a", ServerMetrics{DiskCapacity: 1000}) {
+		t.Fatal("a rejected sample should not replace the last accepted sample used for future comparisons")
+	}
+}
+
+func TestMonotonicityGuard_ResetForgetsLastSample(t *testing.T) {
+	g := NewMonotonicityGuard()
+
+	g.Check("host-a", ServerMetrics{DiskCapacity: 1000})
+	g.Reset("host-a")
+
+	if !g.Check("host-a", ServerMetrics{DiskCapacity: 10}) {
+		t.Fatal("after Reset, the next sample should be treated as the first one seen for that host")
+	}
+}
+
+func TestMonotonicityGuard_ZeroCapacityNeverCountsAsShrink(t *testing.T) {
+	g := NewMonotonicityGuard()
+
+	g.Check("host-a", ServerMetrics{DiskCapacity: 0})
+
+	if !g.Check("host-a", ServerMetrics{DiskCapacity: 1}) {
+		t.Fatal("growth from a zero-valued previous capacity should be accepted, not compared as a shrink")
+	}
+}
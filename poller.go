@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"devops-go-practice/internal/exporter"
+)
+
+// HostPoller owns everything needed to repeatedly poll a single host: its
+// own http.Client (so timeouts and connection pooling never cross hosts)
+// and a running count of consecutive failures. mu guards errorCounter,
+// reported and busy so the aggregator's dispatch loop and a worker running
+// poll() concurrently never race over them.
+type HostPoller struct {
+	host     string
+	client   http.Client
+	registry *exporter.MetricsRegistry
+
+	mu           sync.Mutex
+	errorCounter int
+	reported     bool
+	busy         bool
+}
+
+// NewHostPoller builds a HostPoller for the given "sysstats" endpoint.
+// registry may be nil, in which case poll results are simply not exported.
+func NewHostPoller(host string, registry *exporter.MetricsRegistry) *HostPoller {
+	return &HostPoller{host: host, client: http.Client{Timeout: httpTimeout}, registry: registry}
+}
+
+// exhausted reports whether the host has failed maxRetryCount times in a
+// row and should be dropped from the active poll set.
+func (p *HostPoller) exhausted() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.errorCounter >= maxRetryCount
+}
+
+// markReported flips reported to true and returns whether this call was the
+// one to do so, so the "gave up on host" message is only printed once.
+func (p *HostPoller) markReported() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.reported {
+		return false
+	}
+	p.reported = true
+	return true
+}
+
+// tryAcquire marks the poller busy and reports true, unless a previous
+// poll() for this host is still in flight, in which case it reports false
+// so the dispatch loop can skip re-sending it this tick instead of running
+// two polls against the same host concurrently.
+func (p *HostPoller) tryAcquire() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.busy {
+		return false
+	}
+	p.busy = true
+	return true
+}
+
+// release clears the busy flag set by tryAcquire once poll() has returned.
+func (p *HostPoller) release() {
+	p.mu.Lock()
+	p.busy = false
+	p.mu.Unlock()
+}
+
+// HostResult is a single poll attempt tagged with the host it came from, as
+// fanned in by Aggregator.Run.
+type HostResult struct {
+	Host    string
+	Metrics ServerMetrics
+	Err     error
+}
+
+// poll performs one request/response cycle against the host and parses the
+// response, tracking errorCounter the same way the original single-host
+// startPolling loop did.
+func (p *HostPoller) poll() HostResult {
+	start := time.Now()
+
+	response, err := p.client.Get(p.host)
+
+	p.mu.Lock()
+	p.errorCounter = processResponseError(response, err, p.errorCounter)
+	failed := p.errorCounter > 0
+	p.mu.Unlock()
+
+	if failed {
+		p.recordPollOutcome(start, false)
+		return HostResult{Host: p.host, Err: fmt.Errorf("poll failed for %s", p.host)}
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		p.mu.Lock()
+		p.errorCounter = incrementErrorCount(err, p.errorCounter, "не удалось прочитать тело ответа")
+		p.mu.Unlock()
+		p.recordPollOutcome(start, false)
+		return HostResult{Host: p.host, Err: err}
+	}
+	response.Body.Close()
+
+	metrics, err := parseMetrics(string(body))
+	if err != nil {
+		p.recordPollOutcome(start, false)
+		return HostResult{Host: p.host, Err: err}
+	}
+
+	p.mu.Lock()
+	p.errorCounter = 0
+	p.mu.Unlock()
+
+	p.recordPollOutcome(start, true)
+	p.updateGauges(metrics)
+	return HostResult{Host: p.host, Metrics: metrics}
+}
+
+// recordPollOutcome feeds the poll's latency and success/failure into the
+// MetricsRegistry, mirroring the go-metrics idiom of a resetting timer
+// around each HTTP call plus a counter per outcome.
+func (p *HostPoller) recordPollOutcome(start time.Time, success bool) {
+	if p.registry == nil {
+		return
+	}
+
+	p.registry.ObservePollLatency(p.host, time.Since(start))
+	if success {
+		p.registry.IncCounter("poll_success_total", p.host)
+	} else {
+		p.registry.IncCounter("poll_errors_total", p.host)
+	}
+}
+
+// updateGauges pushes one successful poll's metrics onto the registry's
+// per-host gauges.
+func (p *HostPoller) updateGauges(metrics ServerMetrics) {
+	if p.registry == nil {
+		return
+	}
+
+	memoryUsagePercent, _ := getPercentageUsage(metrics.MemoryCapacity, metrics.MemoryUsage)
+	_, diskFreeMB := getFreeDiskSpace(metrics.DiskCapacity, metrics.DiskUsage)
+	_, networkFreeMbit := getFreeNetworkBandwidth(metrics.NetworkCapacity, metrics.NetworkActivity)
+
+	p.registry.SetGauge("cpu_load", p.host, float64(metrics.CPULoad))
+	p.registry.SetGauge("memory_usage_percent", p.host, float64(memoryUsagePercent))
+	p.registry.SetGauge("disk_free_mb", p.host, float64(diskFreeMB))
+	p.registry.SetGauge("network_free_mbit", p.host, float64(networkFreeMbit))
+}
+
+func processResponseError(response *http.Response, err error, errorCounter int) int {
+	if err != nil {
+		fmt.Printf("Ошибка при отправке запроса: %s\n", err)
+		return incrementErrorCount(err, errorCounter, "")
+	}
+	if response.StatusCode != http.StatusOK {
+		fmt.Printf("Неверный код статуса: %d\n", response.StatusCode)
+		return incrementErrorCount(fmt.Errorf("invalid status code"), errorCounter, "")
+	}
+	return errorCounter
+}
+
+func incrementErrorCount(err error, errorCounter int, message string) int {
+	if message != "" {
+		fmt.Printf("%s: %s\n", message, err)
+	}
+	return errorCounter + 1
+}
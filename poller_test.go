@@ -0,0 +1,44 @@
+package main
+
+import "testing"
+
+func TestHostPoller_TryAcquireBlocksWhileBusy(t *testing.T) {
+	poller := NewHostPoller("http://example.invalid/_stats", nil)
+
+	if !poller.tryAcquire() {
+		t.Fatal("first tryAcquire() on an idle poller should succeed")
+	}
+	if poller.tryAcquire() {
+		t.Fatal("tryAcquire() should report false while a poll is already in flight")
+	}
+
+	poller.release()
+
+	if !poller.tryAcquire() {
+		t.Fatal("tryAcquire() should succeed again once release() has run")
+	}
+}
+
+func TestHostPoller_Exhausted(t *testing.T) {
+	poller := NewHostPoller("http://example.invalid/_stats", nil)
+
+	if poller.exhausted() {
+		t.Fatal("a freshly built poller should not be exhausted")
+	}
+
+	poller.errorCounter = maxRetryCount
+	if !poller.exhausted() {
+		t.Fatal("exhausted() should report true once errorCounter reaches maxRetryCount")
+	}
+}
+
+func TestHostPoller_MarkReportedOnlyFiresOnce(t *testing.T) {
+	poller := NewHostPoller("http://example.invalid/_stats", nil)
+
+	if !poller.markReported() {
+		t.Fatal("first markReported() call should report true")
+	}
+	if poller.markReported() {
+		t.Fatal("second markReported() call should report false")
+	}
+}
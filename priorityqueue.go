@@ -0,0 +1,60 @@
+package main
+
+import "container/heap"
+
+// pqItem wraps a value with the priority it was pushed at.
+type pqItem[T any] struct {
+	value    T
+	priority int
+}
+
+// innerHeap is the container/heap.Interface implementation backing
+// PriorityQueue[T]. Higher priority sorts first (max-heap).
+type innerHeap[T any] []*pqItem[T]
+
+func (h innerHeap[T]) Len() int           { return len(h) }
+func (h innerHeap[T]) Less(i, j int) bool { return h[i].priority > h[j].priority }
+func (h innerHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *innerHeap[T]) Push(x any) {
+	*h = append(*h, x.(*pqItem[T]))
+}
+
+func (h *innerHeap[T]) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// PriorityQueue is a generic, container/heap-based max-heap: Pop always
+// returns the value pushed with the highest priority.
+type PriorityQueue[T any] struct {
+	heap innerHeap[T]
+}
+
+// NewPriorityQueue builds an empty PriorityQueue[T].
+func NewPriorityQueue[T any]() *PriorityQueue[T] {
+	pq := &PriorityQueue[T]{}
+	heap.Init(&pq.heap)
+	return pq
+}
+
+// Push adds value to the queue at the given priority.
+func (pq *PriorityQueue[T]) Push(value T, priority int) {
+	heap.Push(&pq.heap, &pqItem[T]{value: value, priority: priority})
+}
+
+// Pop removes and returns the highest-priority value. ok is false if the
+// queue is empty.
+func (pq *PriorityQueue[T]) Pop() (value T, ok bool) {
+	if pq.heap.Len() == 0 {
+		return value, false
+	}
+	item := heap.Pop(&pq.heap).(*pqItem[T])
+	return item.value, true
+}
+
+// Len reports how many values are queued.
+func (pq *PriorityQueue[T]) Len() int { return pq.heap.Len() }
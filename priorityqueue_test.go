@@ -0,0 +1,65 @@
+package main
+
+import "testing"
+
+func TestPriorityQueue_PopReturnsHighestPriorityFirst(t *testing.T) {
+	pq := NewPriorityQueue[string]()
+	pq.Push("low", 1)
+	pq.Push("high", 10)
+	pq.Push("medium", 5)
+
+	for _, want := range []string{"high", "medium", "low"} {
+		got, ok := pq.Pop()
+		if !ok {
+			t.Fatalf("Pop() reported empty queue before %q was drained", want)
+		}
+		if got != want {
+			t.Fatalf("Pop() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestPriorityQueue_PopOnEmptyQueueReportsNotOK(t *testing.T) {
+	pq := NewPriorityQueue[int]()
+
+	if _, ok := pq.Pop(); ok {
+		t.Fatal("Pop() on an empty queue should report ok=false")
+	}
+}
+
+func TestPriorityQueue_LenTracksPushAndPop(t *testing.T) {
+	pq := NewPriorityQueue[int]()
+	if pq.Len() != 0 {
+		t.Fatalf("Len() = %d, want 0 for a new queue", pq.Len())
+	}
+
+	pq.Push(1, 1)
+	pq.Push(2, 2)
+	if pq.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2 after two pushes", pq.Len())
+	}
+
+	pq.Pop()
+	if pq.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1 after one pop", pq.Len())
+	}
+}
+
+func TestPriorityQueue_EqualPrioritiesAreBothReturned(t *testing.T) {
+	pq := NewPriorityQueue[string]()
+	pq.Push("a", 5)
+	pq.Push("b", 5)
+
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		value, ok := pq.Pop()
+		if !ok {
+			t.Fatalf("Pop() reported empty queue on item %d", i)
+		}
+		seen[value] = true
+	}
+
+	if !seen["a"] || !seen["b"] {
+		t.Fatalf("expected both equal-priority values to come out, got %v", seen)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ThresholdKind says what a Threshold should be compared against: the
+// usagePercent a Metric's checkUsage returns, or its freeResource.
+type ThresholdKind int
+
+const (
+	// ThresholdUsagePercent alerts once usage climbs above Value, e.g. "85%".
+	ThresholdUsagePercent ThresholdKind = iota
+	// ThresholdFreeResource alerts once the free resource drops below
+	// Value, e.g. "2Gi" of free disk.
+	ThresholdFreeResource
+)
+
+// Threshold is a parsed, human-readable limit such as "85%" or "2Gi",
+// together with which of checkUsage's two return values it applies to.
+type Threshold struct {
+	Kind  ThresholdKind
+	Value int
+}
+
+// Breached reports whether usagePercent/freeResource have crossed t.
+func (t Threshold) Breached(usagePercent, freeResource int) bool {
+	if t.Kind == ThresholdFreeResource {
+		return freeResource < t.Value
+	}
+	return usagePercent > t.Value
+}
+
+// quantitySuffixes maps a quantity's unit suffix to how many of a metric's
+// already-mega-scaled base unit (Mb for disk, Mbit/s for network) it's
+// worth. Binary SI (Ki/Mi/Gi) and decimal SI (K/M/G) are both supported,
+// matching Kubernetes-style resource quantities.
+var quantitySuffixes = map[string]float64{
+	"":   1,
+	"M":  1,
+	"Mi": 1,
+	"K":  1.0 / 1000,
+	"Ki": 1.0 / 1024,
+	"G":  1000,
+	"Gi": 1024,
+}
+
+// ParseQuantity parses a Kubernetes-style quantity: a percentage ("85%"),
+// which becomes a ThresholdUsagePercent, or a binary/decimal SI size
+// ("500Mi", "1Gi", "100M"), which becomes a ThresholdFreeResource scaled to
+// the Mb/Mbit/s units checkUsage already reports free resources in.
+func ParseQuantity(raw string) (Threshold, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Threshold{}, fmt.Errorf("empty quantity")
+	}
+
+	if rest, ok := strings.CutSuffix(raw, "%"); ok {
+		value, err := strconv.Atoi(rest)
+		if err != nil {
+			return Threshold{}, fmt.Errorf("invalid percentage quantity %q: %w", raw, err)
+		}
+		return Threshold{Kind: ThresholdUsagePercent, Value: value}, nil
+	}
+
+	number, suffix := splitQuantitySuffix(raw)
+	scale, ok := quantitySuffixes[suffix]
+	if !ok {
+		return Threshold{}, fmt.Errorf("unknown quantity suffix %q in %q", suffix, raw)
+	}
+
+	value, err := strconv.Atoi(number)
+	if err != nil {
+		return Threshold{}, fmt.Errorf("invalid quantity %q: %w", raw, err)
+	}
+
+	return Threshold{Kind: ThresholdFreeResource, Value: int(float64(value) * scale)}, nil
+}
+
+// splitQuantitySuffix splits raw into its leading digits and trailing unit
+// suffix, e.g. "500Mi" -> ("500", "Mi").
+func splitQuantitySuffix(raw string) (number, suffix string) {
+	i := len(raw)
+	for i > 0 && (raw[i-1] < '0' || raw[i-1] > '9') {
+		i--
+	}
+	return raw[:i], raw[i:]
+}
@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Threshold
+	}{
+		{"85%", Threshold{Kind: ThresholdUsagePercent, Value: 85}},
+		{"30%", Threshold{Kind: ThresholdUsagePercent, Value: 30}},
+		{"500Mi", Threshold{Kind: ThresholdFreeResource, Value: 500}},
+		{"1Gi", Threshold{Kind: ThresholdFreeResource, Value: 1024}},
+		{"100M", Threshold{Kind: ThresholdFreeResource, Value: 100}},
+		{"2G", Threshold{Kind: ThresholdFreeResource, Value: 2000}},
+		{"10", Threshold{Kind: ThresholdFreeResource, Value: 10}},
+		{" 2Gi ", Threshold{Kind: ThresholdFreeResource, Value: 2048}},
+	}
+
+	for _, tc := range tests {
+		got, err := ParseQuantity(tc.raw)
+		if err != nil {
+			t.Errorf("ParseQuantity(%q) returned unexpected error: %s", tc.raw, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("ParseQuantity(%q) = %+v, want %+v", tc.raw, got, tc.want)
+		}
+	}
+}
+
+func TestParseQuantity_Errors(t *testing.T) {
+	invalid := []string{"", "  ", "Mi", "%", "85x%", "10Xi", "abc"}
+
+	for _, raw := range invalid {
+		if _, err := ParseQuantity(raw); err == nil {
+			t.Errorf("ParseQuantity(%q) expected an error, got nil", raw)
+		}
+	}
+}
+
+func TestThreshold_Breached(t *testing.T) {
+	usage := Threshold{Kind: ThresholdUsagePercent, Value: 80}
+	if !usage.Breached(81, 0) {
+		t.Error("usage-percent threshold should breach once usagePercent exceeds Value")
+	}
+	if usage.Breached(80, 0) {
+		t.Error("usage-percent threshold should not breach exactly at Value")
+	}
+
+	free := Threshold{Kind: ThresholdFreeResource, Value: 2048}
+	if !free.Breached(0, 2047) {
+		t.Error("free-resource threshold should breach once freeResource drops below Value")
+	}
+	if free.Breached(0, 2048) {
+		t.Error("free-resource threshold should not breach exactly at Value")
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AlertSink delivers a single Alert to some destination. Send is called
+// from a sink's own goroutine (see sinkQueue), so it's free to block on
+// I/O, but should still fail fast rather than hang indefinitely.
+type AlertSink interface {
+	Send(alert Alert) error
+}
+
+// sinkQueueCapacity bounds how many alerts may be pending for a single sink
+// before new alerts start dropping the oldest queued one.
+const sinkQueueCapacity = 64
+
+// sinkQueue buffers alerts for one AlertSink behind a drop-oldest channel,
+// so a slow or failing sink never blocks the polling goroutine.
+type sinkQueue struct {
+	sink   AlertSink
+	alerts chan Alert
+}
+
+func newSinkQueue(sink AlertSink) *sinkQueue {
+	q := &sinkQueue{sink: sink, alerts: make(chan Alert, sinkQueueCapacity)}
+	go q.run()
+	return q
+}
+
+func (q *sinkQueue) run() {
+	for alert := range q.alerts {
+		if err := q.sink.Send(alert); err != nil {
+			fmt.Printf("alert sink error: %s\n", err)
+		}
+	}
+}
+
+// enqueue submits alert, dropping the oldest queued alert first if the
+// sink has fallen behind.
+func (q *sinkQueue) enqueue(alert Alert) {
+	select {
+	case q.alerts <- alert:
+		return
+	default:
+	}
+
+	select {
+	case <-q.alerts:
+	default:
+	}
+
+	select {
+	case q.alerts <- alert:
+	default:
+	}
+}
+
+// StdoutSink prints alerts the same way this tool always has.
+type StdoutSink struct{}
+
+func (StdoutSink) Send(alert Alert) error {
+	fmt.Printf("[%s] %s", alert.Host, alert.Message)
+	return nil
+}
+
+// FileSink appends each alert to path as a JSON line.
+type FileSink struct {
+	path string
+}
+
+// NewFileSink builds a FileSink writing to path, creating it if needed.
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Send(alert Alert) error {
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open alert file: %w", err)
+	}
+	defer file.Close()
+
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("marshal alert: %w", err)
+	}
+
+	_, err = file.Write(append(line, '\n'))
+	return err
+}
+
+// WebhookSink POSTs each alert as JSON to a configured URL, retrying with
+// exponential backoff up to maxRetryCount times.
+type WebhookSink struct {
+	url    string
+	client http.Client
+}
+
+// NewWebhookSink builds a WebhookSink posting to url.
+func NewWebhookSink(url string) *WebhookSink {
+	return &WebhookSink{url: url, client: http.Client{Timeout: httpTimeout}}
+}
+
+func (s *WebhookSink) Send(alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("marshal alert: %w", err)
+	}
+
+	backoff := httpTimeout
+	var lastErr error
+
+	for attempt := 0; attempt < maxRetryCount; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		response, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		response.Body.Close()
+
+		if response.StatusCode >= 300 {
+			lastErr = fmt.Errorf("webhook returned status %d", response.StatusCode)
+			continue
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("webhook delivery failed after %d attempts: %w", maxRetryCount, lastErr)
+}
+
+// AMQPPublisher is the minimal surface AMQPSink needs from a queue client.
+// Keeping it as an interface lets the reconnect loop below stay independent
+// of whichever AMQP client eventually backs it.
+type AMQPPublisher interface {
+	Publish(routingKey string, body []byte) error
+	Close() error
+}
+
+// AMQPDialer opens a new AMQPPublisher, e.g. dialing the broker and
+// declaring a channel/queue.
+type AMQPDialer func() (AMQPPublisher, error)
+
+// AMQPSink publishes alerts to a message queue, reconnecting on failure:
+// open queue, publish, and on error drop the connection so the next Send
+// reopens it.
+type AMQPSink struct {
+	dial       AMQPDialer
+	routingKey string
+
+	mu        sync.Mutex
+	publisher AMQPPublisher
+}
+
+// NewAMQPSink builds an AMQPSink publishing to routingKey, dialing new
+// connections through dial.
+func NewAMQPSink(dial AMQPDialer, routingKey string) *AMQPSink {
+	return &AMQPSink{dial: dial, routingKey: routingKey}
+}
+
+func (s *AMQPSink) Send(alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("marshal alert: %w", err)
+	}
+
+	publisher, err := s.connection()
+	if err != nil {
+		return err
+	}
+
+	if err := publisher.Publish(s.routingKey, body); err != nil {
+		s.mu.Lock()
+		s.publisher = nil
+		s.mu.Unlock()
+		return fmt.Errorf("publish amqp: %w", err)
+	}
+
+	return nil
+}
+
+// connection returns the current publisher, reconnecting with a
+// sleep-and-retry loop (mirroring the polling loop's errorCounter pattern)
+// if none is open.
+func (s *AMQPSink) connection() (AMQPPublisher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.publisher != nil {
+		return s.publisher, nil
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < maxRetryCount; attempt++ {
+		if attempt > 0 {
+			time.Sleep(requestInterval)
+		}
+
+		publisher, err := s.dial()
+		if err == nil {
+			s.publisher = publisher
+			return publisher, nil
+		}
+		lastErr = err
+	}
+
+	return nil, fmt.Errorf("dial amqp after %d attempts: %w", maxRetryCount, lastErr)
+}
+
+// tcpPublisher is a minimal AMQPPublisher stand-in: this tool carries no
+// AMQP client dependency, so it publishes newline-delimited JSON over a
+// plain TCP connection to addr. Swap in a real client's channel here once
+// the project takes on that dependency.
+type tcpPublisher struct {
+	conn net.Conn
+}
+
+func dialAMQP(addr string) AMQPDialer {
+	return func() (AMQPPublisher, error) {
+		conn, err := net.DialTimeout("tcp", addr, httpTimeout)
+		if err != nil {
+			return nil, err
+		}
+		return &tcpPublisher{conn: conn}, nil
+	}
+}
+
+func (p *tcpPublisher) Publish(_ string, body []byte) error {
+	_, err := p.conn.Write(append(body, '\n'))
+	return err
+}
+
+func (p *tcpPublisher) Close() error {
+	return p.conn.Close()
+}
+
+// sinkFlags collects repeated --sink flag values.
+type sinkFlags []string
+
+func (f *sinkFlags) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *sinkFlags) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// buildSinks parses --sink values into AlertSink instances. Unknown or
+// malformed specs are skipped with a warning rather than aborting startup.
+func buildSinks(specs []string) []AlertSink {
+	if len(specs) == 0 {
+		return []AlertSink{StdoutSink{}}
+	}
+
+	sinks := make([]AlertSink, 0, len(specs))
+	for _, spec := range specs {
+		if spec == "stdout" {
+			sinks = append(sinks, StdoutSink{})
+			continue
+		}
+
+		scheme, value, ok := strings.Cut(spec, ":")
+		if !ok {
+			fmt.Printf("некорректный sink (ожидался формат scheme:value): %s\n", spec)
+			continue
+		}
+
+		switch scheme {
+		case "stdout":
+			sinks = append(sinks, StdoutSink{})
+		case "file":
+			sinks = append(sinks, NewFileSink(value))
+		case "webhook":
+			sinks = append(sinks, NewWebhookSink(value))
+		case "amqp":
+			sinks = append(sinks, NewAMQPSink(dialAMQP(value), "alerts"))
+		default:
+			fmt.Printf("неизвестный sink: %s\n", spec)
+		}
+	}
+
+	return sinks
+}
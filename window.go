@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"sync"
+)
+
+// windowConfig controls sliding-window sustained-threshold detection: an
+// alert only fires once at least ratio of the last size samples for a
+// (host, metric) pair are over threshold.
+type windowConfig struct {
+	size  int
+	ratio float64
+}
+
+var defaultWindowConfig = windowConfig{size: 5, ratio: 0.8}
+
+// WindowEvaluator keeps a ring buffer of the last samples per (host,
+// metric) and only reports a sustained breach once enough of them are over
+// threshold, so a single spike no longer fires an alert on its own.
+type WindowEvaluator struct {
+	mu      sync.Mutex
+	config  windowConfig
+	windows map[string][]bool
+}
+
+// NewWindowEvaluator builds a WindowEvaluator using config.
+func NewWindowEvaluator(config windowConfig) *WindowEvaluator {
+	return &WindowEvaluator{config: config, windows: make(map[string][]bool)}
+}
+
+func windowKey(host, metricName string) string {
+	return host + "|" + metricName
+}
+
+// Observe records whether one sample for (host, metric) was over threshold
+// and reports whether the window is now sustained, i.e. at least
+// config.ratio of the last config.size samples were over threshold.
+func (w *WindowEvaluator) Observe(host, metricName string, overThreshold bool) (sustained bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := windowKey(host, metricName)
+	samples := append(w.windows[key], overThreshold)
+	if len(samples) > w.config.size {
+		samples = samples[len(samples)-w.config.size:]
+	}
+	w.windows[key] = samples
+
+	if len(samples) < w.config.size {
+		return false
+	}
+
+	over := 0
+	for _, sample := range samples {
+		if sample {
+			over++
+		}
+	}
+	return float64(over)/float64(len(samples)) >= w.config.ratio
+}
+
+// Reset discards every window tracked for host. It's invoked once polling
+// for a host has failed maxRetryCount times and later recovers, so stale
+// pre-outage samples don't count toward the next alert.
+func (w *WindowEvaluator) Reset(host string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	prefix := host + "|"
+	for key := range w.windows {
+		if strings.HasPrefix(key, prefix) {
+			delete(w.windows, key)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import "testing"
+
+func TestWindowEvaluator_NotSustainedUntilWindowFills(t *testing.T) {
+	w := NewWindowEvaluator(windowConfig{size: 5, ratio: 0.8})
+
+	for i := 0; i < 4; i++ {
+		if sustained := w.Observe("host-a", "disk_usage", true); sustained {
+			t.Fatalf("sample %d: sustained should be false before the window has size samples", i+1)
+		}
+	}
+}
+
+func TestWindowEvaluator_SustainedOnceRatioMet(t *testing.T) {
+	w := NewWindowEvaluator(windowConfig{size: 5, ratio: 0.8})
+
+	samples := []bool{true, true, true, true, false}
+	var sustained bool
+	for _, over := range samples {
+		sustained = w.Observe("host-a", "disk_usage", over)
+	}
+
+	if !sustained {
+		t.Fatal("4/5 over-threshold samples should meet a 0.8 ratio and report sustained")
+	}
+}
+
+func TestWindowEvaluator_NotSustainedBelowRatio(t *testing.T) {
+	w := NewWindowEvaluator(windowConfig{size: 5, ratio: 0.8})
+
+	samples := []bool{true, true, true, false, false}
+	var sustained bool
+	for _, over := range samples {
+		sustained = w.Observe("host-a", "disk_usage", over)
+	}
+
+	if sustained {
+		t.Fatal("3/5 over-threshold samples should fall short of a 0.8 ratio")
+	}
+}
+
+func TestWindowEvaluator_OldSamplesSlideOutOfTheWindow(t *testing.T) {
+	w := NewWindowEvaluator(windowConfig{size: 3, ratio: 0.8})
+
+	w.Observe("host-a", "disk_usage", true)
+	w.Observe("host-a", "disk_usage", true)
+	w.Observe("host-a", "disk_usage", true)
+
+	if sustained := w.Observe("host-a", "disk_usage", false); sustained {
+		t.Fatal("a new under-threshold sample should push the oldest over-threshold sample out of a size-3 window")
+	}
+}
+
+func TestWindowEvaluator_KeyedPerHostAndMetric(t *testing.T) {
+	w := NewWindowEvaluator(windowConfig{size: 2, ratio: 0.5})
+
+	w.Observe("host-a", "disk_usage", true)
+	w.Observe("host-a", "disk_usage", true)
+
+	if sustained := w.Observe("host-b", "disk_usage", false); sustained {
+		t.Fatal("a different host should have its own independent window")
+	}
+	if sustained := w.Observe("host-a", "memory_usage", false); sustained {
+		t.Fatal("a different metric on the same host should have its own independent window")
+	}
+}
+
+func TestWindowEvaluator_ResetDiscardsOnlyThatHost(t *testing.T) {
+	w := NewWindowEvaluator(windowConfig{size: 2, ratio: 0.5})
+
+	w.Observe("host-a", "disk_usage", true)
+	w.Observe("host-b", "disk_usage", true)
+
+	w.Reset("host-a")
+
+	if sustained := w.Observe("host-a", "disk_usage", true); sustained {
+		t.Fatal("after Reset, host-a's window should start over rather than reuse pre-reset samples")
+	}
+	if sustained := w.Observe("host-b", "disk_usage", true); !sustained {
+		t.Fatal("Reset(host-a) should not discard host-b's window")
+	}
+}